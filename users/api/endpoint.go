@@ -0,0 +1,205 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/users"
+)
+
+func verifyEmailEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(verifyEmailReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.VerifyEmail(ctx, req.token); err != nil {
+			return nil, err
+		}
+
+		return verifyEmailRes{}, nil
+	}
+}
+
+func registerEndpoint(svc users.Service, passRegexp *regexp.Regexp) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(registerReq)
+		if err := req.validate(passRegexp); err != nil {
+			return nil, err
+		}
+
+		user := users.User{
+			Email:    req.Email,
+			Username: req.Username,
+			Name:     req.Name,
+			Password: req.Password,
+		}
+
+		if err := svc.Register(ctx, user); err != nil {
+			return nil, err
+		}
+
+		return registerRes{}, nil
+	}
+}
+
+func loginEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(loginReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		token, err := svc.Login(ctx, users.User{Email: req.Email, Password: req.Password})
+		if err != nil {
+			return nil, err
+		}
+
+		return tokenRes{Token: token}, nil
+	}
+}
+
+func changePasswordEndpoint(svc users.Service, passRegexp *regexp.Regexp) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(changePasswordReq)
+		if err := req.validate(passRegexp); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UpdatePassword(ctx, req.token, req.OldPassword, req.Password); err != nil {
+			return nil, err
+		}
+
+		return changePasswordRes{}, nil
+	}
+}
+
+func resetRequestEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(resetRequestReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RequestPasswordReset(ctx, req.Email); err != nil {
+			return nil, err
+		}
+
+		return resetRequestRes{}, nil
+	}
+}
+
+func resetPasswordEndpoint(svc users.Service, passRegexp *regexp.Regexp) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(resetReq)
+		if err := req.validate(passRegexp); err != nil {
+			return nil, err
+		}
+
+		if err := svc.ResetPassword(ctx, req.Token, req.Password); err != nil {
+			return nil, err
+		}
+
+		return resetRes{}, nil
+	}
+}
+
+func updateUsernameEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(usernameReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UpdateUsername(ctx, req.token, req.Username); err != nil {
+			return nil, err
+		}
+
+		return usernameRes{}, nil
+	}
+}
+
+func listUsersEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listUsersReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListUsers(ctx, req.token, req.offset, req.limit, req.email, req.metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		res := usersPageRes{
+			Users:  make([]userRes, len(page.Users)),
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+		}
+		for i, u := range page.Users {
+			res.Users[i] = toUserRes(u)
+		}
+
+		return res, nil
+	}
+}
+
+func updateRoleEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateRoleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UpdateRole(ctx, req.token, req.id, req.Role); err != nil {
+			return nil, err
+		}
+
+		return updateRoleRes{}, nil
+	}
+}
+
+func removeUserEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(removeUserReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveUser(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeUserRes{}, nil
+	}
+}
+
+func createUserEndpoint(svc users.Service, passRegexp *regexp.Regexp) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createUserReq)
+		if err := req.validate(passRegexp); err != nil {
+			return nil, err
+		}
+
+		user := users.User{
+			Email:    req.Email,
+			Username: req.Username,
+			Name:     req.Name,
+			Password: req.Password,
+			Metadata: req.Metadata,
+		}
+
+		created, err := svc.CreateUser(ctx, req.token, user, req.AutoVerify, req.MustChangePassword)
+		if err != nil {
+			return nil, err
+		}
+
+		return createUserRes{toUserRes(created)}, nil
+	}
+}
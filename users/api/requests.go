@@ -0,0 +1,212 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"regexp"
+
+	"github.com/mainflux/mainflux/errors"
+	"github.com/mainflux/mainflux/users"
+)
+
+// registerReq is used by the self-registration endpoint.
+type registerReq struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func (req registerReq) validate(passRegexp *regexp.Regexp) errors.Error {
+	if !passRegexp.MatchString(req.Password) {
+		return users.ErrMalformedPassword
+	}
+
+	return nil
+}
+
+// loginReq is used by the login endpoint, where a registered and verified
+// account exchanges its credentials for an access token.
+type loginReq struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (req loginReq) validate() errors.Error {
+	if req.Email == "" || req.Password == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+// changePasswordReq is used by the password-change endpoint, where the
+// caller must present a valid token and the current password alongside the
+// desired new one.
+type changePasswordReq struct {
+	token       string
+	OldPassword string `json:"old_password"`
+	Password    string `json:"password"`
+}
+
+func (req changePasswordReq) validate(passRegexp *regexp.Regexp) errors.Error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.OldPassword == "" {
+		return users.ErrMalformedEntity
+	}
+
+	if !passRegexp.MatchString(req.Password) {
+		return users.ErrMalformedPassword
+	}
+
+	return nil
+}
+
+// verifyEmailReq is used by the email-verification endpoint.
+type verifyEmailReq struct {
+	token string
+}
+
+func (req verifyEmailReq) validate() errors.Error {
+	if req.token == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// resetRequestReq is used by the password-reset-request endpoint.
+type resetRequestReq struct {
+	Email string `json:"email"`
+}
+
+func (req resetRequestReq) validate() errors.Error {
+	if req.Email == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// resetReq is used by the password-reset endpoint, where the caller
+// exchanges the single-use token emailed to them for a new password.
+type resetReq struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (req resetReq) validate(passRegexp *regexp.Regexp) errors.Error {
+	if req.Token == "" {
+		return users.ErrMalformedEntity
+	}
+
+	if !passRegexp.MatchString(req.Password) {
+		return users.ErrMalformedPassword
+	}
+
+	return nil
+}
+
+// usernameReq is used by the username-change endpoint.
+type usernameReq struct {
+	token    string
+	Username string `json:"username"`
+}
+
+func (req usernameReq) validate() errors.Error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.Username == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// listUsersReq is used by the admin user-listing endpoint.
+type listUsersReq struct {
+	token    string
+	offset   uint64
+	limit    uint64
+	email    string
+	metadata map[string]interface{}
+}
+
+func (req listUsersReq) validate() errors.Error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+// updateRoleReq is used by the admin role-change endpoint.
+type updateRoleReq struct {
+	token string
+	id    string
+	Role  users.Role `json:"role"`
+}
+
+func (req updateRoleReq) validate() errors.Error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return users.ErrMalformedEntity
+	}
+
+	if req.Role != users.RoleUser && req.Role != users.RoleAdmin {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// removeUserReq is used by the admin account-deletion endpoint.
+type removeUserReq struct {
+	token string
+	id    string
+}
+
+func (req removeUserReq) validate() errors.Error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return users.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+// createUserReq is used by the admin account-creation endpoint.
+type createUserReq struct {
+	token              string
+	Email              string                 `json:"email"`
+	Username           string                 `json:"username"`
+	Name               string                 `json:"name"`
+	Password           string                 `json:"password"`
+	Metadata           map[string]interface{} `json:"metadata"`
+	AutoVerify         bool                   `json:"auto_verify"`
+	MustChangePassword bool                   `json:"must_change_password"`
+}
+
+func (req createUserReq) validate(passRegexp *regexp.Regexp) errors.Error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+
+	if !passRegexp.MatchString(req.Password) {
+		return users.ErrMalformedPassword
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+// apiRes is implemented by every response returned by an endpoint, so that
+// encodeResponse can translate it into an HTTP response without endpoint-
+// specific knowledge.
+type apiRes interface {
+	Code() int
+	Headers() map[string]string
+	Empty() bool
+}
+
+// errorRes is the JSON body written for failed requests.
+type errorRes struct {
+	Err string `json:"error"`
+}
+
+type registerRes struct{}
+
+func (res registerRes) Code() int                  { return http.StatusCreated }
+func (res registerRes) Headers() map[string]string { return map[string]string{} }
+func (res registerRes) Empty() bool                { return true }
+
+// tokenRes is the JSON body returned by a successful login.
+type tokenRes struct {
+	Token string `json:"token"`
+}
+
+func (res tokenRes) Code() int                  { return http.StatusCreated }
+func (res tokenRes) Headers() map[string]string { return map[string]string{} }
+func (res tokenRes) Empty() bool                { return false }
+
+type verifyEmailRes struct{}
+
+func (res verifyEmailRes) Code() int                  { return http.StatusOK }
+func (res verifyEmailRes) Headers() map[string]string { return map[string]string{} }
+func (res verifyEmailRes) Empty() bool                { return true }
+
+type changePasswordRes struct{}
+
+func (res changePasswordRes) Code() int                  { return http.StatusOK }
+func (res changePasswordRes) Headers() map[string]string { return map[string]string{} }
+func (res changePasswordRes) Empty() bool                { return true }
+
+type resetRequestRes struct{}
+
+func (res resetRequestRes) Code() int                  { return http.StatusOK }
+func (res resetRequestRes) Headers() map[string]string { return map[string]string{} }
+func (res resetRequestRes) Empty() bool                { return true }
+
+type resetRes struct{}
+
+func (res resetRes) Code() int                  { return http.StatusOK }
+func (res resetRes) Headers() map[string]string { return map[string]string{} }
+func (res resetRes) Empty() bool                { return true }
+
+type usernameRes struct{}
+
+func (res usernameRes) Code() int                  { return http.StatusOK }
+func (res usernameRes) Headers() map[string]string { return map[string]string{} }
+func (res usernameRes) Empty() bool                { return true }
+
+// userRes is the JSON representation of a user account returned to admins.
+type userRes struct {
+	ID       string                 `json:"id"`
+	Username string                 `json:"username,omitempty"`
+	Name     string                 `json:"name,omitempty"`
+	Email    string                 `json:"email"`
+	Role     users.Role             `json:"role"`
+	Verified bool                   `json:"verified"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func toUserRes(u users.User) userRes {
+	return userRes{
+		ID:       u.ID,
+		Username: u.Username,
+		Name:     u.Name,
+		Email:    u.Email,
+		Role:     u.Role,
+		Verified: u.Verified,
+		Metadata: u.Metadata,
+	}
+}
+
+func (res userRes) Code() int                  { return http.StatusOK }
+func (res userRes) Headers() map[string]string { return map[string]string{} }
+func (res userRes) Empty() bool                { return false }
+
+type usersPageRes struct {
+	Users  []userRes `json:"users"`
+	Total  uint64    `json:"total"`
+	Offset uint64    `json:"offset"`
+	Limit  uint64    `json:"limit"`
+}
+
+func (res usersPageRes) Code() int                  { return http.StatusOK }
+func (res usersPageRes) Headers() map[string]string { return map[string]string{} }
+func (res usersPageRes) Empty() bool                { return false }
+
+type updateRoleRes struct{}
+
+func (res updateRoleRes) Code() int                  { return http.StatusOK }
+func (res updateRoleRes) Headers() map[string]string { return map[string]string{} }
+func (res updateRoleRes) Empty() bool                { return true }
+
+type removeUserRes struct{}
+
+func (res removeUserRes) Code() int                  { return http.StatusNoContent }
+func (res removeUserRes) Headers() map[string]string { return map[string]string{} }
+func (res removeUserRes) Empty() bool                { return true }
+
+type createUserRes struct {
+	userRes
+}
+
+func (res createUserRes) Code() int { return http.StatusCreated }
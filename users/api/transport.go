@@ -0,0 +1,265 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package api implements the HTTP transport for the users service.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux/users"
+)
+
+const (
+	contentType  = "application/json"
+	defaultLimit = 10
+)
+
+// MakeHandler returns an HTTP handler for the users service endpoints.
+// passRegexp is the password complexity policy enforced by request
+// validation.
+func MakeHandler(svc users.Service, passRegexp *regexp.Regexp) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	mux := bone.New()
+
+	mux.Post("/users", kithttp.NewServer(
+		registerEndpoint(svc, passRegexp),
+		decodeRegisterRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/tokens", kithttp.NewServer(
+		loginEndpoint(svc),
+		decodeLoginRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/users/verify", kithttp.NewServer(
+		verifyEmailEndpoint(svc),
+		decodeVerifyEmailRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/password", kithttp.NewServer(
+		changePasswordEndpoint(svc, passRegexp),
+		decodeChangePasswordRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/password/reset-request", kithttp.NewServer(
+		resetRequestEndpoint(svc),
+		decodeResetRequestRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/password/reset", kithttp.NewServer(
+		resetPasswordEndpoint(svc, passRegexp),
+		decodeResetRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Patch("/users/username", kithttp.NewServer(
+		updateUsernameEndpoint(svc),
+		decodeUpdateUsernameRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/users", kithttp.NewServer(
+		listUsersEndpoint(svc),
+		decodeListUsersRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/users/admin", kithttp.NewServer(
+		createUserEndpoint(svc, passRegexp),
+		decodeCreateUserRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/users/:id/role", kithttp.NewServer(
+		updateRoleEndpoint(svc),
+		decodeUpdateRoleRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/users/:id", kithttp.NewServer(
+		removeUserEndpoint(svc),
+		decodeRemoveUserRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	return mux
+}
+
+func decodeRegisterRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req registerReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, users.ErrMalformedEntity
+	}
+
+	return req, nil
+}
+
+func decodeLoginRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req loginReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, users.ErrMalformedEntity
+	}
+
+	return req, nil
+}
+
+func decodeVerifyEmailRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return verifyEmailReq{token: r.URL.Query().Get("token")}, nil
+}
+
+func decodeChangePasswordRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req changePasswordReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, users.ErrMalformedEntity
+	}
+	req.token = r.Header.Get("Authorization")
+
+	return req, nil
+}
+
+func decodeResetRequestRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req resetRequestReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, users.ErrMalformedEntity
+	}
+
+	return req, nil
+}
+
+func decodeResetRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req resetReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, users.ErrMalformedEntity
+	}
+
+	return req, nil
+}
+
+func decodeUpdateUsernameRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req usernameReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, users.ErrMalformedEntity
+	}
+	req.token = r.Header.Get("Authorization")
+
+	return req, nil
+}
+
+func decodeListUsersRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+
+	offset, err := strconv.ParseUint(q.Get("offset"), 10, 64)
+	if err != nil {
+		offset = 0
+	}
+
+	limit, err := strconv.ParseUint(q.Get("limit"), 10, 64)
+	if err != nil {
+		limit = defaultLimit
+	}
+
+	var metadata map[string]interface{}
+	if m := q.Get("metadata"); m != "" {
+		if err := json.Unmarshal([]byte(m), &metadata); err != nil {
+			return nil, users.ErrMalformedEntity
+		}
+	}
+
+	return listUsersReq{
+		token:    r.Header.Get("Authorization"),
+		offset:   offset,
+		limit:    limit,
+		email:    q.Get("email"),
+		metadata: metadata,
+	}, nil
+}
+
+func decodeCreateUserRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req createUserReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, users.ErrMalformedEntity
+	}
+	req.token = r.Header.Get("Authorization")
+
+	return req, nil
+}
+
+func decodeUpdateRoleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req updateRoleReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, users.ErrMalformedEntity
+	}
+	req.token = r.Header.Get("Authorization")
+	req.id = bone.GetValue(r, "id")
+
+	return req, nil
+}
+
+func decodeRemoveUserRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return removeUserReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res, ok := response.(apiRes)
+	if !ok {
+		return json.NewEncoder(w).Encode(response)
+	}
+
+	for k, v := range res.Headers() {
+		w.Header().Set(k, v)
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(res.Code())
+
+	if res.Empty() {
+		return nil
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch err {
+	case users.ErrMalformedEntity, users.ErrMalformedPassword, users.ErrUsernameInvalid:
+		w.WriteHeader(http.StatusBadRequest)
+	case users.ErrUnauthorizedAccess, users.ErrUnverifiedAccount, users.ErrExpiredToken, users.ErrPasswordChangeRequired:
+		w.WriteHeader(http.StatusUnauthorized)
+	case users.ErrConflict, users.ErrUsernameDuplicate:
+		w.WriteHeader(http.StatusConflict)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(errorRes{Err: err.Error()})
+}
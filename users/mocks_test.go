@@ -0,0 +1,291 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mainflux/mainflux/errors"
+)
+
+var errNotFound = errors.New("entity not found")
+
+// mockUserRepo is an in-memory UserRepository used to exercise the service
+// layer without a real database.
+type mockUserRepo struct {
+	mu   sync.Mutex
+	byID map[string]User
+}
+
+func newMockUserRepo() *mockUserRepo {
+	return &mockUserRepo{byID: map[string]User{}}
+}
+
+func (m *mockUserRepo) Save(_ context.Context, u User) errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.byID {
+		if existing.Email == u.Email {
+			return ErrConflict
+		}
+	}
+
+	m.byID[u.ID] = u
+	return nil
+}
+
+func (m *mockUserRepo) UpdateUser(_ context.Context, u User) errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.byID[u.ID]; !ok {
+		return errNotFound
+	}
+	m.byID[u.ID] = u
+	return nil
+}
+
+func (m *mockUserRepo) RetrieveByID(_ context.Context, id string) (User, errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.byID[id]
+	if !ok {
+		return User{}, errNotFound
+	}
+	return u, nil
+}
+
+func (m *mockUserRepo) RetrieveByEmail(_ context.Context, email string) (User, errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, u := range m.byID {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return User{}, errNotFound
+}
+
+func (m *mockUserRepo) RetrieveByUsername(_ context.Context, username string) (User, errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, u := range m.byID {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return User{}, errNotFound
+}
+
+func (m *mockUserRepo) UpdatePassword(_ context.Context, id, password string) errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.byID[id]
+	if !ok {
+		return errNotFound
+	}
+	u.Password = password
+	u.MustChangePassword = false
+	m.byID[id] = u
+	return nil
+}
+
+func (m *mockUserRepo) UpdateUsername(_ context.Context, id, username string) errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.byID[id]
+	if !ok {
+		return errNotFound
+	}
+	u.Username = username
+	m.byID[id] = u
+	return nil
+}
+
+func (m *mockUserRepo) UpdateRole(_ context.Context, id string, role Role) errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.byID[id]
+	if !ok {
+		return errNotFound
+	}
+	u.Role = role
+	m.byID[id] = u
+	return nil
+}
+
+func (m *mockUserRepo) Remove(_ context.Context, id string) errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.byID, id)
+	return nil
+}
+
+func (m *mockUserRepo) RetrieveAll(_ context.Context, offset, limit uint64, email string, metadata map[string]interface{}) (UserPage, errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, len(m.byID))
+	for id := range m.byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var matched []User
+	for _, id := range ids {
+		u := m.byID[id]
+		if email != "" && !strings.Contains(u.Email, email) {
+			continue
+		}
+		if !matchesMetadata(u.Metadata, metadata) {
+			continue
+		}
+		matched = append(matched, u)
+	}
+
+	total := uint64(len(matched))
+	if offset >= total {
+		return UserPage{Total: total, Offset: offset, Limit: limit}, nil
+	}
+
+	end := offset + limit
+	if limit == 0 || end > total {
+		end = total
+	}
+
+	return UserPage{
+		Users:  matched[offset:end],
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+	}, nil
+}
+
+// matchesMetadata reports whether have contains every key/value pair in
+// want (an exact match on the requested subset, not a deep equality check).
+func matchesMetadata(have, want map[string]interface{}) bool {
+	for k, v := range want {
+		if have[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *mockUserRepo) Total(_ context.Context) (uint64, errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return uint64(len(m.byID)), nil
+}
+
+func (m *mockUserRepo) VerifyEmail(_ context.Context, id string) errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.byID[id]
+	if !ok {
+		return errNotFound
+	}
+	u.Verified = true
+	m.byID[id] = u
+	return nil
+}
+
+// mockResetRepo is an in-memory PasswordResetRepository.
+type mockResetRepo struct {
+	mu      sync.Mutex
+	byToken map[string]PasswordReset
+	saveErr errors.Error
+}
+
+func newMockResetRepo() *mockResetRepo {
+	return &mockResetRepo{byToken: map[string]PasswordReset{}}
+}
+
+func (m *mockResetRepo) Save(_ context.Context, r PasswordReset) errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.byToken[r.Token] = r
+	return nil
+}
+
+func (m *mockResetRepo) Retrieve(_ context.Context, hashedToken string) (PasswordReset, errors.Error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.byToken[hashedToken]
+	if !ok {
+		return PasswordReset{}, errNotFound
+	}
+	return r, nil
+}
+
+func (m *mockResetRepo) Remove(_ context.Context, hashedToken string) errors.Error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.byToken, hashedToken)
+	return nil
+}
+
+// mockHasher is a trivial, insecure Hasher used only by tests.
+type mockHasher struct{}
+
+func (mockHasher) Hash(s string) (string, errors.Error) {
+	return "hashed:" + s, nil
+}
+
+func (mockHasher) Compare(plain, hash string) errors.Error {
+	if "hashed:"+plain != hash {
+		return ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+// mockIdentityProvider is a trivial IdentityProvider used only by tests: the
+// access token is just the issuer prefixed with a fixed marker.
+type mockIdentityProvider struct{}
+
+func (mockIdentityProvider) TemporaryKey(issuer string) (string, errors.Error) {
+	return "key:" + issuer, nil
+}
+
+func (mockIdentityProvider) Identity(key string) (string, errors.Error) {
+	const prefix = "key:"
+	if len(key) <= len(prefix) || key[:len(prefix)] != prefix {
+		return "", ErrUnauthorizedAccess
+	}
+	return key[len(prefix):], nil
+}
+
+// mockUUIDProvider hands out sequential, predictable identifiers.
+type mockUUIDProvider struct {
+	mu   sync.Mutex
+	next int
+}
+
+func (p *mockUUIDProvider) ID() (string, errors.Error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.next++
+	return fmt.Sprintf("id-%d", p.next), nil
+}
@@ -0,0 +1,32 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/errors"
+)
+
+// PasswordReset represents a single-use, time-limited token issued in
+// response to a password-reset request. Token is stored hashed so that a
+// compromised database dump cannot be used to reset accounts.
+type PasswordReset struct {
+	UserID    string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// PasswordResetRepository specifies persistence for password-reset tokens.
+type PasswordResetRepository interface {
+	// Save persists a newly issued password-reset token.
+	Save(context.Context, PasswordReset) errors.Error
+
+	// Retrieve looks up a reset token by its hash.
+	Retrieve(_ context.Context, hashedToken string) (PasswordReset, errors.Error)
+
+	// Remove invalidates the given reset token so it cannot be reused.
+	Remove(_ context.Context, hashedToken string) errors.Error
+}
@@ -0,0 +1,506 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/errors"
+	"github.com/mainflux/mainflux/users/emailer"
+)
+
+// defaultPassRegexp is applied when the service is constructed without an
+// explicit password policy (i.e. MF_USERS_PASS_REGEX is unset); it preserves
+// the previous minimum-length-only behaviour.
+var defaultPassRegexp = regexp.MustCompile(fmt.Sprintf(`^.{%d,}$`, minPassLen))
+
+// verifyTokenDuration is the validity window of an email-verification token.
+const verifyTokenDuration = 24 * time.Hour
+
+// resetTokenDuration is the validity window of a password-reset token.
+const resetTokenDuration = time.Hour
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// invalid email address).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrMalformedPassword indicates that the supplied password does not
+	// satisfy the configured complexity policy.
+	ErrMalformedPassword = errors.New("password does not meet complexity requirements")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrConflict indicates that a user with the given identity already
+	// exists.
+	ErrConflict = errors.New("entity already exists")
+
+	// ErrUnverifiedAccount indicates that the account exists but its email
+	// has not yet been confirmed.
+	ErrUnverifiedAccount = errors.New("account email is not verified")
+
+	// ErrExpiredToken indicates that a single-use token (e.g. for email
+	// verification or password reset) is no longer valid.
+	ErrExpiredToken = errors.New("token is expired or no longer valid")
+
+	// ErrUsernameInvalid indicates that the requested username does not
+	// satisfy the allowed length or character set.
+	ErrUsernameInvalid = errors.New("username is invalid")
+
+	// ErrUsernameDuplicate indicates that the requested username is already
+	// taken by another account.
+	ErrUsernameDuplicate = errors.New("username already taken")
+
+	// ErrPasswordChangeRequired indicates that the account was created with
+	// a temporary password and must change it before taking any other
+	// action.
+	ErrPasswordChangeRequired = errors.New("password change required")
+)
+
+// Hasher specifies an API for generating hashes of an arbitrary textual
+// content.
+type Hasher interface {
+	// Hash generates the hashed string from plain-text.
+	Hash(string) (string, errors.Error)
+
+	// Compare compares plain-text password with a hash and returns a
+	// non-nil error if they do not match.
+	Compare(string, string) errors.Error
+}
+
+// IdentityProvider specifies an API for generating and validating access
+// tokens (i.e. JWTs) issued for an authenticated user. The issuer is the
+// user's ID (a UUID), not its email, so that a user's access token remains
+// valid across email or username changes.
+type IdentityProvider interface {
+	// TemporaryKey generates the access token for the given issuer.
+	TemporaryKey(issuer string) (string, errors.Error)
+
+	// Identity extracts the issuer (user ID) stored in the given access
+	// token.
+	Identity(key string) (string, errors.Error)
+}
+
+// UUIDProvider specifies an API for generating unique user identifiers.
+type UUIDProvider interface {
+	// ID generates a new, unique identifier.
+	ID() (string, errors.Error)
+}
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Register creates new user account. In case of the failed registration,
+	// a non-nil error value is returned.
+	Register(ctx context.Context, user User) errors.Error
+
+	// Login authenticates the user given its credentials. Successful
+	// authentication generates a new access token. Failed invocations are
+	// identified by the non-nil error value in the response.
+	Login(ctx context.Context, user User) (string, errors.Error)
+
+	// ViewUser retrieves the user identified by the given token.
+	ViewUser(ctx context.Context, token string) (User, errors.Error)
+
+	// UpdateUser updates the metadata of the user identified by the given
+	// token.
+	UpdateUser(ctx context.Context, token string, user User) errors.Error
+
+	// UpdatePassword updates the password of the user identified by the
+	// given token, provided that oldPassword matches the current one.
+	UpdatePassword(ctx context.Context, token, oldPassword, password string) errors.Error
+
+	// UpdateUsername changes the login handle of the user identified by the
+	// given token.
+	UpdateUsername(ctx context.Context, token, username string) errors.Error
+
+	// VerifyEmail confirms ownership of the email address associated with
+	// the given verification token, so that the account may authenticate.
+	VerifyEmail(ctx context.Context, token string) errors.Error
+
+	// RequestPasswordReset emails a single-use reset link to email, if an
+	// account with that address exists. It always returns nil so that
+	// callers cannot use it to probe for account existence.
+	RequestPasswordReset(ctx context.Context, email string) errors.Error
+
+	// ResetPassword validates a reset token issued by RequestPasswordReset,
+	// consumes it, and sets password as the account's new password.
+	ResetPassword(ctx context.Context, token, password string) errors.Error
+
+	// Authorize returns a non-nil error unless the user identified by token
+	// holds at least the given role.
+	Authorize(ctx context.Context, token string, role Role) errors.Error
+
+	// ListUsers retrieves a page of accounts, optionally filtered by a
+	// substring of their email and/or exact metadata match. Restricted to
+	// RoleAdmin.
+	ListUsers(ctx context.Context, token string, offset, limit uint64, email string, metadata map[string]interface{}) (UserPage, errors.Error)
+
+	// UpdateRole changes the role of the user with the given id.
+	// Restricted to RoleAdmin.
+	UpdateRole(ctx context.Context, token, id string, role Role) errors.Error
+
+	// RemoveUser deletes the user with the given id. Restricted to
+	// RoleAdmin.
+	RemoveUser(ctx context.Context, token, id string) errors.Error
+
+	// CreateUser creates a new account on behalf of an administrator. When
+	// autoVerify is set the account is immediately treated as verified;
+	// when mustChangePassword is set, the account cannot take any other
+	// action until it picks its own password. Restricted to RoleAdmin.
+	CreateUser(ctx context.Context, token string, user User, autoVerify, mustChangePassword bool) (User, errors.Error)
+}
+
+type usersService struct {
+	users      UserRepository
+	resets     PasswordResetRepository
+	idProvider UUIDProvider
+	hasher     Hasher
+	idp        IdentityProvider
+	mailer     emailer.Mailer
+	baseURL    string
+	secret     string
+	passRegexp *regexp.Regexp
+}
+
+// NewService instantiates the users service implementation. baseURL is the
+// publicly reachable origin (e.g. "https://mainflux.example.com") used to
+// build the links sent in verification and password-reset emails. passRegex
+// is the password complexity policy (e.g. sourced from the
+// MF_USERS_PASS_REGEX environment variable); when empty, the service falls
+// back to the previous minimum-length-only policy. secret signs the
+// single-use tokens issued for email verification and password reset. A
+// non-nil error is returned if passRegex fails to compile.
+func NewService(users UserRepository, resets PasswordResetRepository, idProvider UUIDProvider, hasher Hasher, idp IdentityProvider, mailer emailer.Mailer, baseURL, secret, passRegex string) (Service, errors.Error) {
+	re := defaultPassRegexp
+	if passRegex != "" {
+		compiled, err := regexp.Compile(passRegex)
+		if err != nil {
+			return nil, errors.Wrap(ErrMalformedPassword, errors.New(err.Error()))
+		}
+		re = compiled
+	}
+
+	return &usersService{
+		users:      users,
+		resets:     resets,
+		idProvider: idProvider,
+		hasher:     hasher,
+		idp:        idp,
+		mailer:     mailer,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		secret:     secret,
+		passRegexp: re,
+	}, nil
+}
+
+// verificationLink builds the clickable URL emailed to a newly registered
+// account, pointing back at the email-verification endpoint.
+func (svc usersService) verificationLink(token string) string {
+	return fmt.Sprintf("%s/users/verify?token=%s", svc.baseURL, url.QueryEscape(token))
+}
+
+// resetLink builds the clickable URL emailed in response to a password-reset
+// request, pointing back at the password-reset endpoint.
+func (svc usersService) resetLink(token string) string {
+	return fmt.Sprintf("%s/password/reset?token=%s", svc.baseURL, url.QueryEscape(token))
+}
+
+func (svc usersService) Register(ctx context.Context, user User) errors.Error {
+	if err := user.Validate(); err != nil {
+		return err
+	}
+
+	if !svc.passRegexp.MatchString(user.Password) {
+		return ErrMalformedPassword
+	}
+
+	if user.Username != "" {
+		if _, err := svc.users.RetrieveByUsername(ctx, user.Username); err == nil {
+			return ErrUsernameDuplicate
+		}
+	}
+
+	id, err := svc.idProvider.ID()
+	if err != nil {
+		return err
+	}
+	user.ID = id
+
+	hash, err := svc.hasher.Hash(user.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
+	user.Role = RoleUser
+	user.Verified = false
+	user.VerifyToken = signToken(svc.secret, user.ID, verifyTokenDuration)
+
+	if err := svc.users.Save(ctx, user); err != nil {
+		return err
+	}
+
+	if svc.mailer != nil {
+		svc.mailer.Send(emailer.VerificationEmail(user.Email, svc.verificationLink(user.VerifyToken)))
+	}
+
+	return nil
+}
+
+func (svc usersService) Login(ctx context.Context, user User) (string, errors.Error) {
+	dbUser, err := svc.users.RetrieveByEmail(ctx, user.Email)
+	if err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	if err := svc.hasher.Compare(user.Password, dbUser.Password); err != nil {
+		return "", ErrUnauthorizedAccess
+	}
+
+	if !dbUser.Verified {
+		return "", ErrUnverifiedAccount
+	}
+
+	return svc.idp.TemporaryKey(dbUser.ID)
+}
+
+func (svc usersService) VerifyEmail(ctx context.Context, token string) errors.Error {
+	id, err := verifyToken(svc.secret, token)
+	if err != nil {
+		return err
+	}
+
+	return svc.users.VerifyEmail(ctx, id)
+}
+
+func (svc usersService) RequestPasswordReset(ctx context.Context, email string) errors.Error {
+	// An unknown email is deliberately treated the same as success, so that
+	// callers cannot use this endpoint to probe for account existence.
+	user, err := svc.users.RetrieveByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	token := signToken(svc.secret, user.ID, resetTokenDuration)
+	reset := PasswordReset{
+		UserID:    user.ID,
+		Token:     hashToken(token),
+		ExpiresAt: time.Now().Add(resetTokenDuration),
+	}
+
+	// Unlike the lookup above, a failure here is a real infra error, not an
+	// unknown email, so it must not pass silently.
+	if err := svc.resets.Save(ctx, reset); err != nil {
+		log.Printf("users: failed to save password reset token for %s: %s", user.Email, err)
+		return nil
+	}
+
+	if svc.mailer != nil {
+		svc.mailer.Send(emailer.ResetEmail(user.Email, svc.resetLink(token)))
+	}
+
+	return nil
+}
+
+func (svc usersService) ResetPassword(ctx context.Context, token, password string) errors.Error {
+	id, err := verifyToken(svc.secret, token)
+	if err != nil {
+		return err
+	}
+
+	reset, err := svc.resets.Retrieve(ctx, hashToken(token))
+	if err != nil {
+		return ErrExpiredToken
+	}
+
+	if reset.UserID != id || time.Now().After(reset.ExpiresAt) {
+		return ErrExpiredToken
+	}
+
+	if !svc.passRegexp.MatchString(password) {
+		return ErrMalformedPassword
+	}
+
+	hash, err := svc.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.users.UpdatePassword(ctx, id, hash); err != nil {
+		return err
+	}
+
+	return svc.resets.Remove(ctx, hashToken(token))
+}
+
+// authenticated resolves the token to the underlying account and rejects
+// accounts that still owe a password change.
+func (svc usersService) authenticated(ctx context.Context, token string) (User, errors.Error) {
+	id, err := svc.idp.Identity(token)
+	if err != nil {
+		return User{}, ErrUnauthorizedAccess
+	}
+
+	user, err := svc.users.RetrieveByID(ctx, id)
+	if err != nil {
+		return User{}, ErrUnauthorizedAccess
+	}
+
+	if user.MustChangePassword {
+		return User{}, ErrPasswordChangeRequired
+	}
+
+	return user, nil
+}
+
+func (svc usersService) ViewUser(ctx context.Context, token string) (User, errors.Error) {
+	return svc.authenticated(ctx, token)
+}
+
+func (svc usersService) UpdateUser(ctx context.Context, token string, user User) errors.Error {
+	dbUser, err := svc.authenticated(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	user.ID = dbUser.ID
+	return svc.users.UpdateUser(ctx, user)
+}
+
+func (svc usersService) UpdatePassword(ctx context.Context, token, oldPassword, password string) errors.Error {
+	id, err := svc.idp.Identity(token)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if !svc.passRegexp.MatchString(password) {
+		return ErrMalformedPassword
+	}
+
+	dbUser, err := svc.users.RetrieveByID(ctx, id)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if err := svc.hasher.Compare(oldPassword, dbUser.Password); err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	hash, err := svc.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return svc.users.UpdatePassword(ctx, id, hash)
+}
+
+func (svc usersService) UpdateUsername(ctx context.Context, token, username string) errors.Error {
+	user, err := svc.authenticated(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if username == user.Username {
+		return nil
+	}
+
+	if !usernameRegexp.MatchString(username) {
+		return ErrUsernameInvalid
+	}
+
+	if existing, err := svc.users.RetrieveByUsername(ctx, username); err == nil && existing.ID != user.ID {
+		return ErrUsernameDuplicate
+	}
+
+	return svc.users.UpdateUsername(ctx, user.ID, username)
+}
+
+func (svc usersService) Authorize(ctx context.Context, token string, role Role) errors.Error {
+	user, err := svc.authenticated(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if role == RoleAdmin && user.Role != RoleAdmin {
+		return ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+func (svc usersService) ListUsers(ctx context.Context, token string, offset, limit uint64, email string, metadata map[string]interface{}) (UserPage, errors.Error) {
+	if err := svc.Authorize(ctx, token, RoleAdmin); err != nil {
+		return UserPage{}, err
+	}
+
+	return svc.users.RetrieveAll(ctx, offset, limit, email, metadata)
+}
+
+func (svc usersService) UpdateRole(ctx context.Context, token, id string, role Role) errors.Error {
+	if err := svc.Authorize(ctx, token, RoleAdmin); err != nil {
+		return err
+	}
+
+	return svc.users.UpdateRole(ctx, id, role)
+}
+
+func (svc usersService) RemoveUser(ctx context.Context, token, id string) errors.Error {
+	if err := svc.Authorize(ctx, token, RoleAdmin); err != nil {
+		return err
+	}
+
+	return svc.users.Remove(ctx, id)
+}
+
+func (svc usersService) CreateUser(ctx context.Context, token string, user User, autoVerify, mustChangePassword bool) (User, errors.Error) {
+	if err := svc.Authorize(ctx, token, RoleAdmin); err != nil {
+		return User{}, err
+	}
+
+	if err := user.Validate(); err != nil {
+		return User{}, err
+	}
+
+	if !svc.passRegexp.MatchString(user.Password) {
+		return User{}, ErrMalformedPassword
+	}
+
+	id, err := svc.idProvider.ID()
+	if err != nil {
+		return User{}, err
+	}
+	user.ID = id
+
+	hash, err := svc.hasher.Hash(user.Password)
+	if err != nil {
+		return User{}, err
+	}
+	user.Password = hash
+
+	if user.Role == "" {
+		user.Role = RoleUser
+	}
+	user.Verified = autoVerify
+	user.MustChangePassword = mustChangePassword
+	if !autoVerify {
+		user.VerifyToken = signToken(svc.secret, user.ID, verifyTokenDuration)
+	}
+
+	if err := svc.users.Save(ctx, user); err != nil {
+		return User{}, err
+	}
+
+	if !autoVerify && svc.mailer != nil {
+		svc.mailer.Send(emailer.VerificationEmail(user.Email, svc.verificationLink(user.VerifyToken)))
+	}
+
+	return user, nil
+}
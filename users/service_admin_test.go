@@ -0,0 +1,151 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"testing"
+)
+
+func seedUser(t *testing.T, repo *mockUserRepo, id, email string, role Role) string {
+	t.Helper()
+
+	if err := repo.Save(context.Background(), User{ID: id, Email: email, Role: role, Password: "hashed:whatever", Verified: true}); err != nil {
+		t.Fatalf("unexpected error seeding user: %s", err)
+	}
+
+	token, _ := mockIdentityProvider{}.TemporaryKey(id)
+	return token
+}
+
+func TestAuthorizeRejectsNonAdmin(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	userToken := seedUser(t, userRepo, "id-1", "jane@example.com", RoleUser)
+
+	if err := svc.Authorize(context.Background(), userToken, RoleAdmin); err != ErrUnauthorizedAccess {
+		t.Fatalf("got error %v, want %v", err, ErrUnauthorizedAccess)
+	}
+}
+
+func TestAuthorizeAllowsAdmin(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	adminToken := seedUser(t, userRepo, "id-1", "admin@example.com", RoleAdmin)
+
+	if err := svc.Authorize(context.Background(), adminToken, RoleAdmin); err != nil {
+		t.Fatalf("got error %v, want nil for an admin token", err)
+	}
+}
+
+func TestListUsersRejectsNonAdmin(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	userToken := seedUser(t, userRepo, "id-1", "jane@example.com", RoleUser)
+
+	if _, err := svc.ListUsers(context.Background(), userToken, 0, 10, "", nil); err != ErrUnauthorizedAccess {
+		t.Fatalf("got error %v, want %v", err, ErrUnauthorizedAccess)
+	}
+}
+
+func TestListUsersFiltersForAdmin(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	adminToken := seedUser(t, userRepo, "id-1", "admin@example.com", RoleAdmin)
+	seedUser(t, userRepo, "id-2", "jane@example.com", RoleUser)
+	seedUser(t, userRepo, "id-3", "john@example.com", RoleUser)
+
+	page, err := svc.ListUsers(context.Background(), adminToken, 0, 10, "jane", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if page.Total != 1 || len(page.Users) != 1 || page.Users[0].Email != "jane@example.com" {
+		t.Fatalf("got page %+v, want a single match for jane@example.com", page)
+	}
+}
+
+func TestUpdateRoleRejectsNonAdmin(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	userToken := seedUser(t, userRepo, "id-1", "jane@example.com", RoleUser)
+	seedUser(t, userRepo, "id-2", "john@example.com", RoleUser)
+
+	if err := svc.UpdateRole(context.Background(), userToken, "id-2", RoleAdmin); err != ErrUnauthorizedAccess {
+		t.Fatalf("got error %v, want %v", err, ErrUnauthorizedAccess)
+	}
+}
+
+func TestUpdateRoleAdminSuccess(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	adminToken := seedUser(t, userRepo, "id-1", "admin@example.com", RoleAdmin)
+	seedUser(t, userRepo, "id-2", "john@example.com", RoleUser)
+
+	if err := svc.UpdateRole(context.Background(), adminToken, "id-2", RoleAdmin); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	u, err := userRepo.RetrieveByID(context.Background(), "id-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if u.Role != RoleAdmin {
+		t.Fatalf("got role %q, want %q", u.Role, RoleAdmin)
+	}
+}
+
+func TestRemoveUserRejectsNonAdmin(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	userToken := seedUser(t, userRepo, "id-1", "jane@example.com", RoleUser)
+	seedUser(t, userRepo, "id-2", "john@example.com", RoleUser)
+
+	if err := svc.RemoveUser(context.Background(), userToken, "id-2"); err != ErrUnauthorizedAccess {
+		t.Fatalf("got error %v, want %v", err, ErrUnauthorizedAccess)
+	}
+}
+
+func TestRemoveUserAdminSuccess(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	adminToken := seedUser(t, userRepo, "id-1", "admin@example.com", RoleAdmin)
+	seedUser(t, userRepo, "id-2", "john@example.com", RoleUser)
+
+	if err := svc.RemoveUser(context.Background(), adminToken, "id-2"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := userRepo.RetrieveByID(context.Background(), "id-2"); err == nil {
+		t.Fatalf("got nil error, want the removed user to no longer be retrievable")
+	}
+}
+
+func TestCreateUserRejectsNonAdmin(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	userToken := seedUser(t, userRepo, "id-1", "jane@example.com", RoleUser)
+
+	_, err := svc.CreateUser(context.Background(), userToken, User{Email: "new@example.com", Password: "Sup3rSecret!"}, true, false)
+	if err != ErrUnauthorizedAccess {
+		t.Fatalf("got error %v, want %v", err, ErrUnauthorizedAccess)
+	}
+}
+
+func TestCreateUserAdminSuccess(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	adminToken := seedUser(t, userRepo, "id-1", "admin@example.com", RoleAdmin)
+
+	created, err := svc.CreateUser(context.Background(), adminToken, User{Email: "new@example.com", Password: "Sup3rSecret!"}, true, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if created.ID == "" {
+		t.Fatalf("got empty ID, want a generated one")
+	}
+	if !created.Verified {
+		t.Fatalf("got Verified = false, want true (autoVerify was requested)")
+	}
+	if !created.MustChangePassword {
+		t.Fatalf("got MustChangePassword = false, want true")
+	}
+
+	stored, err := userRepo.RetrieveByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error retrieving created user: %s", err)
+	}
+	if stored.Email != "new@example.com" {
+		t.Fatalf("got email %q, want %q", stored.Email, "new@example.com")
+	}
+}
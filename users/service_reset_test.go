@@ -0,0 +1,43 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"testing"
+)
+
+func newTestService(t *testing.T, resets *mockResetRepo) (Service, *mockUserRepo) {
+	t.Helper()
+
+	userRepo := newMockUserRepo()
+	svc, err := NewService(userRepo, resets, &mockUUIDProvider{}, mockHasher{}, mockIdentityProvider{}, nil, "http://localhost", "secret", "")
+	if err != nil {
+		t.Fatalf("unexpected error constructing service: %s", err)
+	}
+
+	return svc, userRepo
+}
+
+func TestRequestPasswordResetUnknownEmailIsMasked(t *testing.T) {
+	svc, _ := newTestService(t, newMockResetRepo())
+
+	if err := svc.RequestPasswordReset(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("got error %v, want nil (unknown email must be masked)", err)
+	}
+}
+
+func TestRequestPasswordResetSaveFailureStillMasksButDoesNotPanic(t *testing.T) {
+	resets := newMockResetRepo()
+	resets.saveErr = errNotFound
+	svc, userRepo := newTestService(t, resets)
+
+	if err := userRepo.Save(context.Background(), User{ID: "id-1", Email: "jane@example.com", Password: "hashed:whatever"}); err != nil {
+		t.Fatalf("unexpected error seeding user: %s", err)
+	}
+
+	if err := svc.RequestPasswordReset(context.Background(), "jane@example.com"); err != nil {
+		t.Fatalf("got error %v, want nil even when persisting the reset token fails", err)
+	}
+}
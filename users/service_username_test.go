@@ -0,0 +1,56 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUpdateUsernameNoOpIsNotADuplicate(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	ctx := context.Background()
+
+	if err := userRepo.Save(ctx, User{ID: "id-1", Email: "jane@example.com", Username: "jane", Password: "hashed:whatever"}); err != nil {
+		t.Fatalf("unexpected error seeding user: %s", err)
+	}
+
+	token, _ := mockIdentityProvider{}.TemporaryKey("id-1")
+
+	if err := svc.UpdateUsername(ctx, token, "jane"); err != nil {
+		t.Fatalf("got error %v, want nil when renaming to the account's own username", err)
+	}
+}
+
+func TestUpdateUsernameRejectsTakenName(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	ctx := context.Background()
+
+	if err := userRepo.Save(ctx, User{ID: "id-1", Email: "jane@example.com", Username: "jane", Password: "hashed:whatever"}); err != nil {
+		t.Fatalf("unexpected error seeding user: %s", err)
+	}
+	if err := userRepo.Save(ctx, User{ID: "id-2", Email: "john@example.com", Username: "john", Password: "hashed:whatever"}); err != nil {
+		t.Fatalf("unexpected error seeding user: %s", err)
+	}
+
+	token, _ := mockIdentityProvider{}.TemporaryKey("id-2")
+
+	if err := svc.UpdateUsername(ctx, token, "jane"); err != ErrUsernameDuplicate {
+		t.Fatalf("got error %v, want %v", err, ErrUsernameDuplicate)
+	}
+}
+
+func TestRegisterRejectsDuplicateUsername(t *testing.T) {
+	svc, userRepo := newTestService(t, newMockResetRepo())
+	ctx := context.Background()
+
+	if err := userRepo.Save(ctx, User{ID: "id-1", Email: "jane@example.com", Username: "jane", Password: "hashed:whatever"}); err != nil {
+		t.Fatalf("unexpected error seeding user: %s", err)
+	}
+
+	err := svc.Register(ctx, User{Email: "john@example.com", Username: "jane", Password: "Sup3rSecret!"})
+	if err != ErrUsernameDuplicate {
+		t.Fatalf("got error %v, want %v", err, ErrUsernameDuplicate)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSignVerifyTokenRoundTrip(t *testing.T) {
+	payload, err := verifyToken("secret", signToken("secret", "user-1", time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error verifying a freshly signed token: %s", err)
+	}
+	if payload != "user-1" {
+		t.Fatalf("got payload %q, want %q", payload, "user-1")
+	}
+}
+
+func TestVerifyTokenExpired(t *testing.T) {
+	token := signToken("secret", "user-1", -time.Minute)
+
+	if _, err := verifyToken("secret", token); err != ErrExpiredToken {
+		t.Fatalf("got error %v, want %v", err, ErrExpiredToken)
+	}
+}
+
+func TestVerifyTokenWrongSecret(t *testing.T) {
+	token := signToken("secret", "user-1", time.Hour)
+
+	if _, err := verifyToken("other-secret", token); err != ErrMalformedEntity {
+		t.Fatalf("got error %v, want %v", err, ErrMalformedEntity)
+	}
+}
+
+func TestVerifyTokenMalformed(t *testing.T) {
+	if _, err := verifyToken("secret", "not-a-token"); err != ErrMalformedEntity {
+		t.Fatalf("got error %v, want %v", err, ErrMalformedEntity)
+	}
+}
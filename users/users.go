@@ -23,27 +23,62 @@ const (
 )
 
 var (
-	userRegexp    = regexp.MustCompile("^[a-zA-Z0-9!#$%&'*+/=?^_`{|}~.-]+$")
-	hostRegexp    = regexp.MustCompile("^[^\\s]+\\.[^\\s]+$")
-	userDotRegexp = regexp.MustCompile("(^[.]{1})|([.]{1}$)|([.]{2,})")
+	userRegexp     = regexp.MustCompile("^[a-zA-Z0-9!#$%&'*+/=?^_`{|}~.-]+$")
+	hostRegexp     = regexp.MustCompile("^[^\\s]+\\.[^\\s]+$")
+	userDotRegexp  = regexp.MustCompile("(^[.]{1})|([.]{1}$)|([.]{2,})")
+	usernameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]{3,32}$`)
 )
 
-// User represents a Mainflux user account. Each user is identified given its
-// email and password.
+// Role determines what a user is authorized to do.
+type Role string
+
+const (
+	// RoleUser is assigned to every self-registered account.
+	RoleUser Role = "user"
+
+	// RoleAdmin grants access to the admin-only user-management endpoints.
+	RoleAdmin Role = "admin"
+)
+
+// User represents a Mainflux user account. ID is the stable identifier
+// (a UUID) assigned at registration and used as the JWT subject; Username
+// is a mutable, unique login handle that can be changed without affecting
+// ID or Email. A newly registered account is unverified until its owner
+// confirms the email via VerifyToken; unverified accounts cannot
+// authenticate. MustChangePassword is set on accounts created by an admin
+// that have not yet picked their own password.
 type User struct {
-	Email    string
-	Password string
-	Metadata map[string]interface{}
+	ID                 string
+	Username           string
+	Name               string
+	Email              string
+	Password           string
+	Metadata           map[string]interface{}
+	Role               Role
+	Verified           bool
+	VerifyToken        string
+	MustChangePassword bool
+}
+
+// UserPage is a paginated slice of user accounts, as returned by
+// UserRepository.RetrieveAll.
+type UserPage struct {
+	Users  []User
+	Total  uint64
+	Offset uint64
+	Limit  uint64
 }
 
-// Validate returns an error if user representation is invalid.
+// Validate returns an error if user representation is invalid. Password
+// complexity is enforced by the service layer, which applies a configurable
+// policy, rather than here.
 func (u User) Validate() errors.Error {
 	if !isEmail(u.Email) {
 		return ErrMalformedEntity
 	}
 
-	if len(u.Password) < minPassLen {
-		return ErrMalformedEntity
+	if u.Username != "" && !usernameRegexp.MatchString(u.Username) {
+		return ErrUsernameInvalid
 	}
 
 	return nil
@@ -58,11 +93,39 @@ type UserRepository interface {
 	// Update updates the user metadata.
 	UpdateUser(context.Context, User) errors.Error
 
-	// RetrieveByID retrieves user by its unique identifier (i.e. email).
+	// RetrieveByID retrieves user by its unique identifier (a UUID).
 	RetrieveByID(context.Context, string) (User, errors.Error)
 
-	// UpdatePassword updates password for user with given email
-	UpdatePassword(_ context.Context, email, password string) errors.Error
+	// RetrieveByEmail retrieves user by its email address.
+	RetrieveByEmail(context.Context, string) (User, errors.Error)
+
+	// RetrieveByUsername retrieves user by its username.
+	RetrieveByUsername(context.Context, string) (User, errors.Error)
+
+	// UpdatePassword updates password for user with given id. It also clears
+	// any pending MustChangePassword flag.
+	UpdatePassword(_ context.Context, id, password string) errors.Error
+
+	// UpdateUsername changes the username of the user with given id. A
+	// non-nil error is returned if the new username is already taken.
+	UpdateUsername(_ context.Context, id, username string) errors.Error
+
+	// UpdateRole changes the role of the user with given id.
+	UpdateRole(_ context.Context, id string, role Role) errors.Error
+
+	// Remove deletes the user with given id.
+	Remove(_ context.Context, id string) errors.Error
+
+	// RetrieveAll retrieves a page of users, optionally filtered by a
+	// substring of their email and/or exact metadata match.
+	RetrieveAll(ctx context.Context, offset, limit uint64, email string, metadata map[string]interface{}) (UserPage, errors.Error)
+
+	// Total returns the number of users in the repository.
+	Total(ctx context.Context) (uint64, errors.Error)
+
+	// VerifyEmail marks the user with the given id as verified, so that it
+	// is allowed to authenticate.
+	VerifyEmail(_ context.Context, id string) errors.Error
 }
 
 func isEmail(email string) bool {